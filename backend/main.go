@@ -1,21 +1,52 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"keycloak-basic-backend/middleware"
+	"keycloak-basic-backend/middleware/oidc"
+	"keycloak-basic-backend/middleware/revocation"
+	"keycloak-basic-backend/middleware/tokenclient"
 	"net/http"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	fmt.Println("Discovering OIDC configuration...")
+	discovery, err := oidc.Discover(middleware.KeycloakURL())
+	if err != nil {
+		panic(fmt.Sprintf("Failed to discover OIDC configuration: %v", err))
+	}
+	middleware.SetDiscoveredEndpoints(
+		discovery.AuthorizationEndpoint,
+		discovery.TokenEndpoint,
+		discovery.IntrospectionEndpoint,
+		discovery.EndSessionEndpoint,
+		discovery.JWKSURI,
+	)
+	fmt.Println("✓ OIDC configuration discovered")
+
 	fmt.Println("Initializing JWT verification...")
 	if err := middleware.GetKeycloakPublicKey(); err != nil {
 		panic(fmt.Sprintf("Failed to initialize JWT: %v", err))
 	}
 	fmt.Println("✓ JWT initialized successfully")
 
+	revocationStore := revocation.NewMemoryStore()
+	middleware.SetRevocationStore(revocationStore)
+	go runRevocationGC(revocationStore)
+
+	oidcClient := oidc.NewClient(
+		discovery,
+		middleware.ClientID(),
+		middleware.ClientSecret(),
+		"http://localhost:9000/auth/callback",
+		[]byte("replace-with-a-real-secret-in-production"),
+	)
+
 	router := gin.Default()
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
@@ -27,6 +58,10 @@ func main() {
 
 	router.GET("/api/todos/public", getTodosPublic)
 
+	// Exchanges Keycloak credentials for a token pair, for callers that can't
+	// do the full OIDC redirect dance (e.g. scripts, the todo demo itself).
+	router.POST("/api/token", issueToken)
+
 	// Method 1: JWT Validation (Fast, Offline)
 	// Validates token cryptographically without calling Keycloak
 	// ✅ Faster performance (no network call)
@@ -41,6 +76,22 @@ func main() {
 	// ❌ Slower (requires network call to Keycloak)
 	router.GET("/api/todos/private/token-introspect", middleware.TokenIntrospectionMiddleware(), getTodosPrivate)
 
+	// Revokes the caller's current JWT (and optionally all of their
+	// sessions), giving the offline JWT path revocation semantics.
+	router.POST("/api/tokens/revoke", middleware.JWTAuthMiddleware(), middleware.RevokeTokenHandler())
+
+	// Demonstrates role-gated access: only callers whose JWT carries the
+	// realm "admin" role get through. RequireRoles is JWT-only (see
+	// middleware/authz.go), so it must run after JWTAuthMiddleware
+	// specifically, never after TokenIntrospectionMiddleware.
+	router.GET("/api/todos/admin", middleware.JWTAuthMiddleware(), middleware.RequireRoles("admin"), getTodosPrivate)
+
+	// Full OIDC Authorization Code + PKCE flow, for browser-based clients
+	// that shouldn't have to hand-craft bearer tokens themselves.
+	router.GET("/auth/login", oidcClient.LoginHandler())
+	router.GET("/auth/callback", oidcClient.CallbackHandler())
+	router.POST("/auth/logout", oidcClient.LogoutHandler())
+
 	serverPort := ":9000"
 	fmt.Printf("🚀 Server starting on %s\n", serverPort)
 
@@ -77,6 +128,43 @@ var todos = []Todo{
 	},
 }
 
+// runRevocationGC periodically prunes expired entries from store so the
+// revocation list doesn't grow unbounded.
+func runRevocationGC(store *revocation.MemoryStore) {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := store.GC(context.Background()); err != nil {
+			fmt.Printf("⚠️  revocation store GC failed: %v\n", err)
+		}
+	}
+}
+
+func issueToken(c *gin.Context) {
+	var req struct {
+		Username string `json:"username" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "username and password are required",
+		})
+		return
+	}
+
+	tokenResp, err := tokenclient.PasswordGrant(req.Username, req.Password)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":  "Failed to obtain token",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenResp)
+}
+
 func getTodosPublic(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Public endpoint - no authentication required",
@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ===========================
+// Role/Scope Authorization
+// ===========================
+//
+// RequireScopes and RequireResourcePermission run after either
+// JWTAuthMiddleware or TokenIntrospectionMiddleware: both populate "scopes"
+// in the gin context, and RequireResourcePermission only needs the bearer
+// token itself. RequireRoles is JWT-only — Keycloak's introspection response
+// doesn't carry realm_access/resource_access, so TokenIntrospectionMiddleware
+// has no roles to put in the context.
+
+// RequireRoles returns a gin.HandlerFunc that allows the request through
+// only if the authenticated user's realm roles include all of roles. It must
+// run after JWTAuthMiddleware specifically — TokenIntrospectionMiddleware
+// doesn't populate "realm_roles", so chaining this after it rejects every
+// request.
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRoles, _ := c.Get("realm_roles")
+		have := toStringSet(userRoles)
+
+		missing := missingFrom(have, roles)
+		if len(missing) > 0 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":         "Missing required role(s)",
+				"missing_roles": missing,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScopes returns a gin.HandlerFunc that allows the request through
+// only if the token's space-separated scope claim includes all of scopes.
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userScopes, _ := c.Get("scopes")
+		have := toStringSet(userScopes)
+
+		missing := missingFrom(have, scopes)
+		if len(missing) > 0 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":          "Missing required scope(s)",
+				"missing_scopes": missing,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func toStringSet(v interface{}) map[string]bool {
+	set := make(map[string]bool)
+	if values, ok := v.([]string); ok {
+		for _, value := range values {
+			set[value] = true
+		}
+	}
+	return set
+}
+
+func missingFrom(have map[string]bool, want []string) []string {
+	var missing []string
+	for _, w := range want {
+		if !have[w] {
+			missing = append(missing, w)
+		}
+	}
+	return missing
+}
+
+// ===========================
+// UMA 2.0 Resource Permissions
+// ===========================
+
+// umaDecisionTTL bounds how long a cached UMA decision is trusted before
+// RequireResourcePermission re-checks with Keycloak, so a permission revoked
+// in Keycloak takes effect within this window instead of being cached
+// forever. Mirrors introspectionCacheTTL in tokencache.go.
+const umaDecisionTTL = 30 * time.Second
+
+// umaDecision is a cached UMA authorization decision with the time it was
+// made, so the cache can expire it.
+type umaDecision struct {
+	allowed  bool
+	cachedAt time.Time
+}
+
+// umaDecisionCache is a small per-user LRU cache of UMA authorization
+// decisions, so RequireResourcePermission doesn't round-trip to Keycloak on
+// every request for the same user/resource/scope.
+type umaDecisionCache struct {
+	mu        sync.Mutex
+	order     []string
+	decisions map[string]umaDecision
+	capacity  int
+}
+
+func newUMADecisionCache(capacity int) *umaDecisionCache {
+	return &umaDecisionCache{
+		decisions: make(map[string]umaDecision),
+		capacity:  capacity,
+	}
+}
+
+func (c *umaDecisionCache) get(key string) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	decision, ok := c.decisions[key]
+	if !ok {
+		return false, false
+	}
+	if time.Since(decision.cachedAt) > umaDecisionTTL {
+		delete(c.decisions, key)
+		return false, false
+	}
+	return decision.allowed, true
+}
+
+func (c *umaDecisionCache) set(key string, allowed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.decisions[key]; !exists {
+		if len(c.order) >= c.capacity {
+			oldest := c.order[0]
+			c.order = c.order[1:]
+			delete(c.decisions, oldest)
+		}
+		c.order = append(c.order, key)
+	}
+	c.decisions[key] = umaDecision{allowed: allowed, cachedAt: time.Now()}
+}
+
+// globalUMACache caches UMA decisions across requests for up to 1000
+// distinct user/resource/scope combinations.
+var globalUMACache = newUMADecisionCache(1000)
+
+// RequireResourcePermission authorizes the request against Keycloak's UMA 2.0
+// Authorization Services by requesting a ticket for "resource#scope". It
+// works after either JWTAuthMiddleware or TokenIntrospectionMiddleware, since
+// it only needs the bearer token itself (for the UMA ticket request).
+func RequireResourcePermission(resource, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := extractBearerToken(c)
+		if !ok {
+			return
+		}
+
+		// Keyed by a hash of the token itself, not context state like
+		// "user_id" — that's set by whichever auth middleware ran first, and
+		// trusting it here would let callers on a route with no (or a
+		// not-yet-run) auth middleware collapse onto the same cache key.
+		cacheKey := fmt.Sprintf("%s:%s#%s", hashToken(token), resource, scope)
+
+		allowed, found := globalUMACache.get(cacheKey)
+		if !found {
+			var err error
+			allowed, err = checkUMAPermission(token, resource, scope)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":  "Failed to evaluate resource permission",
+					"detail": err.Error(),
+				})
+				c.Abort()
+				return
+			}
+			globalUMACache.set(cacheKey, allowed)
+		}
+
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":    "Missing required permission",
+				"resource": resource,
+				"scope":    scope,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// checkUMAPermission asks Keycloak's token endpoint for a UMA ticket covering
+// resource#scope, using the caller's bearer token as the subject token. A 200
+// response means the permission is granted; 401/403 means it is not.
+func checkUMAPermission(token, resource, scope string) (bool, error) {
+	data := url.Values{}
+	data.Set("grant_type", "urn:ietf:params:oauth:grant-type:uma-ticket")
+	data.Set("audience", serverClientID)
+	data.Set("permission", fmt.Sprintf("%s#%s", resource, scope))
+
+	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return false, fmt.Errorf("failed to create UMA ticket request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to request UMA ticket: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return false, nil
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("UMA ticket request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+}
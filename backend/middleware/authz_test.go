@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestToStringSet(t *testing.T) {
+	cases := []struct {
+		name string
+		in   interface{}
+		want map[string]bool
+	}{
+		{"nil", nil, map[string]bool{}},
+		{"wrong type", 42, map[string]bool{}},
+		{"empty slice", []string{}, map[string]bool{}},
+		{"values", []string{"a", "b"}, map[string]bool{"a": true, "b": true}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := toStringSet(tc.in); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("toStringSet(%v) = %v, want %v", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMissingFrom(t *testing.T) {
+	have := map[string]bool{"a": true, "b": true}
+
+	missing := missingFrom(have, []string{"a", "b"})
+	if len(missing) != 0 {
+		t.Errorf("missingFrom with all present = %v, want empty", missing)
+	}
+
+	missing = missingFrom(have, []string{"a", "c", "d"})
+	sort.Strings(missing)
+	want := []string{"c", "d"}
+	if !reflect.DeepEqual(missing, want) {
+		t.Errorf("missingFrom = %v, want %v", missing, want)
+	}
+
+	missing = missingFrom(map[string]bool{}, []string{"a"})
+	if !reflect.DeepEqual(missing, []string{"a"}) {
+		t.Errorf("missingFrom with empty have = %v, want [a]", missing)
+	}
+}
+
+func TestUMADecisionCacheExpiresAfterTTL(t *testing.T) {
+	c := newUMADecisionCache(10)
+	c.set("key", true)
+
+	if allowed, found := c.get("key"); !found || !allowed {
+		t.Fatalf("get() = %v, %v; want true, true", allowed, found)
+	}
+
+	// Simulate the entry having aged past umaDecisionTTL.
+	c.mu.Lock()
+	entry := c.decisions["key"]
+	entry.cachedAt = time.Now().Add(-umaDecisionTTL - time.Second)
+	c.decisions["key"] = entry
+	c.mu.Unlock()
+
+	if _, found := c.get("key"); found {
+		t.Error("get() returned a decision past its TTL")
+	}
+}
+
+func TestUMADecisionCacheEvictsOldestOverCapacity(t *testing.T) {
+	c := newUMADecisionCache(2)
+	c.set("a", true)
+	c.set("b", true)
+	c.set("c", true)
+
+	if _, found := c.get("a"); found {
+		t.Error("get(a) found an entry that should have been evicted")
+	}
+	if _, found := c.get("b"); !found {
+		t.Error("get(b) did not find an entry that should still be cached")
+	}
+	if _, found := c.get("c"); !found {
+		t.Error("get(c) did not find an entry that should still be cached")
+	}
+}
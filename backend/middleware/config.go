@@ -4,11 +4,50 @@ const (
 	// Keycloak server and realm configuration
 	keycloakURL = "http://localhost:8080/realms/users" // Keycloak realm URL
 
-	// JWT-specific endpoints
-	jwksURL = keycloakURL + "/protocol/openid-connect/certs" // JWKS endpoint for JWT verification
+	// Client configuration
+	serverClientID     = "36601c4e-2027-41f9-b02e-c6a06e20d171" // Replace with actual client ID from Keycloak
+	serverClientSecret = "TPuXmlD9X4nzLb5toUTi6MnmWUtoT88U"     // Replace with actual client secret from Keycloak
+)
 
-	// Token Introspection-specific configuration
-	keycloakIntrospectURL = keycloakURL + "/protocol/openid-connect/token/introspect" // Token introspection endpoint
-	serverClientID        = "36601c4e-2027-41f9-b02e-c6a06e20d171"                    // Replace with actual client ID from Keycloak
-	serverClientSecret    = "TPuXmlD9X4nzLb5toUTi6MnmWUtoT88U"                        // Replace with actual client secret from Keycloak
+// Endpoint variables default to Keycloak's well-known path layout, but are
+// overwritten by SetDiscoveredEndpoints once the OIDC discovery document has
+// been fetched at startup (see middleware/oidc and main.go). Keeping the
+// hardcoded defaults means the package still works before discovery runs, or
+// if it's skipped entirely (e.g. in tests).
+var (
+	jwksURL               = keycloakURL + "/protocol/openid-connect/certs"
+	keycloakIntrospectURL = keycloakURL + "/protocol/openid-connect/token/introspect"
+	tokenURL              = keycloakURL + "/protocol/openid-connect/token"
+	authorizationURL      = keycloakURL + "/protocol/openid-connect/auth"
+	endSessionURL         = keycloakURL + "/protocol/openid-connect/logout"
 )
+
+// SetDiscoveredEndpoints overrides the hardcoded endpoint defaults with the
+// values published in a Keycloak realm's /.well-known/openid-configuration
+// document, so deployments with a non-default path layout work without code
+// changes. Empty arguments leave the corresponding default in place.
+func SetDiscoveredEndpoints(authorization, token, introspection, endSession, jwks string) {
+	if authorization != "" {
+		authorizationURL = authorization
+	}
+	if token != "" {
+		tokenURL = token
+	}
+	if introspection != "" {
+		keycloakIntrospectURL = introspection
+	}
+	if endSession != "" {
+		endSessionURL = endSession
+	}
+	if jwks != "" {
+		jwksURL = jwks
+	}
+}
+
+// Exported accessors so subpackages (e.g. middleware/tokenclient,
+// middleware/oidc) can reach the shared Keycloak client configuration
+// without duplicating constants.
+func KeycloakURL() string  { return keycloakURL }
+func ClientID() string     { return serverClientID }
+func ClientSecret() string { return serverClientSecret }
+func TokenURL() string     { return tokenURL }
@@ -11,12 +11,22 @@ import (
 // Helper Functions
 // ===========================
 
-// extractBearerToken extracts the Bearer token from the Authorization header
+// SessionCookieName is the cookie the OIDC login flow (middleware/oidc)
+// stores the access token in, so browser-based callers that completed
+// /auth/login don't have to hand-craft an Authorization header.
+const SessionCookieName = "session"
+
+// extractBearerToken extracts the access token from the Authorization header
+// (Bearer scheme), falling back to the SessionCookieName cookie set by the
+// OIDC login flow when no Authorization header is present.
 // Returns the token string and a boolean indicating success
 func extractBearerToken(c *gin.Context) (string, bool) {
-	// Step 1: Check if Authorization header exists
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
+		if sessionToken, err := c.Cookie(SessionCookieName); err == nil && sessionToken != "" {
+			return sessionToken, true
+		}
+
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "Authorization header required",
 		})
@@ -24,7 +34,7 @@ func extractBearerToken(c *gin.Context) (string, bool) {
 		return "", false
 	}
 
-	// Step 2: Extract Bearer token from "Bearer <token>" format
+	// Extract Bearer token from "Bearer <token>" format
 	parts := strings.Split(authHeader, " ")
 	if len(parts) != 2 || parts[0] != "Bearer" {
 		c.JSON(http.StatusUnauthorized, gin.H{
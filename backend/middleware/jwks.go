@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ===========================
+// JWKS Manager
+// ===========================
+//
+// JWKSManager keeps a local cache of Keycloak's signing keys fresh without
+// requiring a server restart when Keycloak rotates them. It refreshes on a
+// timer, honors Cache-Control: max-age from the JWKS response when present,
+// and falls back to the last-known-good keys if a refresh fails. A lookup for
+// an unrecognized kid triggers an on-demand refresh (rate-limited so a burst
+// of requests bearing a new kid doesn't stampede Keycloak).
+
+const (
+	defaultJWKSRefreshInterval = 10 * time.Minute
+	minOnDemandRefreshInterval = 30 * time.Second
+)
+
+// JWKSManager fetches and caches Keycloak's public keys, keeping them fresh
+// via a background refresh loop. The zero value is not usable; construct one
+// with NewJWKSManager.
+type JWKSManager struct {
+	mu sync.RWMutex
+
+	jwksURL         string
+	httpClient      *http.Client
+	keys            map[string]*rsa.PublicKey
+	refreshInterval time.Duration
+	lastOnDemand    time.Time
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+// NewJWKSManager fetches the initial key set from jwksURL and starts a
+// background goroutine that refreshes it on refreshInterval (or sooner, if
+// Keycloak's response advertises a shorter Cache-Control max-age).
+func NewJWKSManager(jwksURL string) (*JWKSManager, error) {
+	m := &JWKSManager{
+		jwksURL:         jwksURL,
+		httpClient:      &http.Client{Timeout: 10 * time.Second},
+		keys:            make(map[string]*rsa.PublicKey),
+		refreshInterval: defaultJWKSRefreshInterval,
+		stopCh:          make(chan struct{}),
+		doneCh:          make(chan struct{}),
+	}
+
+	if err := m.refresh(); err != nil {
+		return nil, err
+	}
+
+	go m.refreshLoop()
+
+	return m, nil
+}
+
+func (m *JWKSManager) refreshLoop() {
+	defer close(m.doneCh)
+
+	m.mu.RLock()
+	interval := m.refreshInterval
+	m.mu.RUnlock()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := m.refresh(); err != nil {
+				fmt.Printf("⚠️  JWKS refresh failed, keeping last-known-good keys: %v\n", err)
+			}
+			m.mu.RLock()
+			newInterval := m.refreshInterval
+			m.mu.RUnlock()
+			if newInterval != interval {
+				interval = newInterval
+				ticker.Reset(interval)
+			}
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// refresh fetches the JWKS and, on success, swaps in the new key set. On
+// failure it leaves any previously cached keys in place so verification can
+// keep working against the last-known-good set.
+func (m *JWKSManager) refresh() error {
+	jwks, maxAge, err := fetchJWKS(m.jwksURL, m.httpClient)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		pubKey, err := jwkToRSAPublicKey(key)
+		if err != nil {
+			return fmt.Errorf("failed to convert JWK to RSA public key: %w", err)
+		}
+		keys[key.Kid] = pubKey
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	if maxAge > 0 {
+		m.refreshInterval = maxAge
+	}
+	m.mu.Unlock()
+
+	return nil
+}
+
+// Key returns the public key for kid, refreshing on demand (at most once per
+// minOnDemandRefreshInterval) if kid isn't in the current cache. This covers
+// the gap between a Keycloak key rotation and the next scheduled refresh.
+func (m *JWKSManager) Key(kid string) (*rsa.PublicKey, bool) {
+	m.mu.RLock()
+	key, ok := m.keys[kid]
+	m.mu.RUnlock()
+	if ok {
+		return key, true
+	}
+
+	m.mu.Lock()
+	if time.Since(m.lastOnDemand) < minOnDemandRefreshInterval {
+		m.mu.Unlock()
+		return nil, false
+	}
+	m.lastOnDemand = time.Now()
+	m.mu.Unlock()
+
+	if err := m.refresh(); err != nil {
+		fmt.Printf("⚠️  on-demand JWKS refresh for kid %q failed: %v\n", kid, err)
+		return nil, false
+	}
+
+	m.mu.RLock()
+	key, ok = m.keys[kid]
+	m.mu.RUnlock()
+	return key, ok
+}
+
+// Close stops the background refresh loop. It blocks until the loop has
+// exited, so it's safe to call during a clean shutdown.
+func (m *JWKSManager) Close() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
+	<-m.doneCh
+}
+
+// fetchJWKS fetches the JSON Web Key Set from url, returning the refresh
+// interval implied by the response's Cache-Control max-age (0 if absent).
+func fetchJWKS(url string, client *http.Client) (*JWKS, time.Duration, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("failed to fetch JWKS: status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read JWKS response: %w", err)
+	}
+
+	jwks, err := parseJWKS(body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return jwks, maxAgeFromCacheControl(resp.Header.Get("Cache-Control")), nil
+}
+
+// maxAgeFromCacheControl extracts the max-age directive from a Cache-Control
+// header value, returning 0 if it's absent or malformed.
+func maxAgeFromCacheControl(header string) time.Duration {
+	for _, directive := range strings.Split(header, ",") {
+		directive = strings.TrimSpace(directive)
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if err != nil || seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaxAgeFromCacheControl(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		want   time.Duration
+	}{
+		{"absent", "", 0},
+		{"simple max-age", "max-age=600", 600 * time.Second},
+		{"with other directives", "public, max-age=120, must-revalidate", 120 * time.Second},
+		{"extra whitespace", "  max-age=60  ", 60 * time.Second},
+		{"zero is treated as absent", "max-age=0", 0},
+		{"negative is malformed", "max-age=-5", 0},
+		{"non-numeric is malformed", "max-age=soon", 0},
+		{"no max-age directive", "no-cache, no-store", 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := maxAgeFromCacheControl(tc.header); got != tc.want {
+				t.Errorf("maxAgeFromCacheControl(%q) = %v, want %v", tc.header, got, tc.want)
+			}
+		})
+	}
+}
@@ -5,9 +5,9 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
 	"math/big"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -67,37 +67,63 @@ type CustomClaims struct {
 	PreferredUsername string `json:"preferred_username"`
 	Email             string `json:"email"`
 	Name              string `json:"name"`
+	// Nonce is only present on OIDC id_tokens; see middleware/oidc.
+	Nonce string `json:"nonce,omitempty"`
+
+	// RealmAccess, ResourceAccess, and Scope back the role/scope checks in
+	// authz.go.
+	RealmAccess    RealmAccess               `json:"realm_access"`
+	ResourceAccess map[string]ResourceAccess `json:"resource_access"`
+	Scope          string                    `json:"scope"`
+}
+
+// RealmAccess mirrors Keycloak's realm_access claim.
+type RealmAccess struct {
+	Roles []string `json:"roles"`
+}
+
+// ResourceAccess mirrors one client's entry in Keycloak's resource_access claim.
+type ResourceAccess struct {
+	Roles []string `json:"roles"`
 }
 
 // ===========================
 // Global Variables
 // ===========================
 
-var publicKeys map[string]*rsa.PublicKey
+// jwksManager holds the cached Keycloak signing keys. It refreshes itself in
+// the background; see jwks.go for the rotation/refresh logic.
+var jwksManager *JWKSManager
 
 // ===========================
 // Initialization
 // ===========================
 
 // GetKeycloakPublicKey fetches public keys from Keycloak for JWT verification
+// and starts the background refresh loop that keeps them current across key
+// rotations, without requiring a server restart.
 func GetKeycloakPublicKey() error {
-	// Fetch JWKS (JSON Web Key Set) from Keycloak
-	keys, err := fetchJWKS()
+	manager, err := NewJWKSManager(jwksURL)
 	if err != nil {
-		return fmt.Errorf("failed to fetch JWKS: %w", err)
+		return fmt.Errorf("failed to initialize JWKS manager: %w", err)
 	}
+	jwksManager = manager
+	return nil
+}
 
-	// Convert JWKs to RSA public keys
-	publicKeys = make(map[string]*rsa.PublicKey)
-	for _, key := range keys.Keys {
-		pubKey, err := jwkToRSAPublicKey(key)
-		if err != nil {
-			return fmt.Errorf("failed to convert JWK to RSA public key: %w", err)
-		}
-		publicKeys[key.Kid] = pubKey
-	}
+// GlobalJWKSManager returns the package's shared JWKSManager, or nil if
+// GetKeycloakPublicKey hasn't been called yet. Other packages (e.g. the OIDC
+// login flow) use this to verify tokens against the same cached key set.
+func GlobalJWKSManager() *JWKSManager {
+	return jwksManager
+}
 
-	return nil
+// CloseJWKSManager stops the background JWKS refresh loop. Call it during a
+// clean shutdown.
+func CloseJWKSManager() {
+	if jwksManager != nil {
+		jwksManager.Close()
+	}
 }
 
 // ===========================
@@ -120,8 +146,10 @@ func verifyToken(tokenString string) (*CustomClaims, error) {
 			return nil, fmt.Errorf("kid header not found in token")
 		}
 
-		// Get the corresponding public key
-		publicKey, exists := publicKeys[kid]
+		// Get the corresponding public key. Key() transparently triggers an
+		// on-demand refresh if kid isn't cached yet, covering the window
+		// right after Keycloak rotates its signing keys.
+		publicKey, exists := jwksManager.Key(kid)
 		if !exists {
 			return nil, fmt.Errorf("public key not found for kid: %s", kid)
 		}
@@ -171,28 +199,12 @@ func verifyToken(tokenString string) (*CustomClaims, error) {
 // Helper Functions
 // ===========================
 
-// fetchJWKS fetches the JSON Web Key Set from Keycloak
-func fetchJWKS() (*JWKS, error) {
-	resp, err := http.Get(jwksURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch JWKS: status code %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read JWKS response: %w", err)
-	}
-
+// parseJWKS parses a raw JWKS response body
+func parseJWKS(body []byte) (*JWKS, error) {
 	var jwks JWKS
 	if err := json.Unmarshal(body, &jwks); err != nil {
 		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
 	}
-
 	return &jwks, nil
 }
 
@@ -258,9 +270,59 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Consult the introspection cache so a signature-valid token that was
+		// since revoked (and caught by a prior /token-introspect call) is
+		// still rejected here, without paying the network round-trip.
+		if cached, found := globalIntrospectionCache.get(hashToken(token)); found {
+			if !cached.active || time.Now().After(cached.exp) {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error":  "Token has been revoked",
+					"method": "JWT Validation",
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		// Reject tokens that have been explicitly revoked, either by jti or
+		// by a subject-wide revocation (see revoke.go).
+		if revocationStore != nil {
+			revoked, err := revocationStore.IsRevoked(claims.ID)
+			if err == nil && !revoked {
+				revoked, err = revocationStore.IsSubjectRevoked(claims.Subject)
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":  "Failed to check token revocation status",
+					"detail": err.Error(),
+					"method": "JWT Validation",
+				})
+				c.Abort()
+				return
+			}
+			if revoked {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error":  "Token has been revoked",
+					"method": "JWT Validation",
+				})
+				c.Abort()
+				return
+			}
+		}
+
 		// Store user info in context for use in handlers
 		c.Set("user_id", claims.Subject)
 		c.Set("email", claims.Email)
+		c.Set("jti", claims.ID)
+		if claims.ExpiresAt != nil {
+			c.Set("exp", claims.ExpiresAt.Time)
+		}
+
+		// Store role/scope info for RequireRoles/RequireScopes/
+		// RequireResourcePermission (see authz.go).
+		c.Set("realm_roles", claims.RealmAccess.Roles)
+		c.Set("resource_access", claims.ResourceAccess)
+		c.Set("scopes", strings.Fields(claims.Scope))
 
 		// Token is valid, continue to the next handler
 		c.Next()
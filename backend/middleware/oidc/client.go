@@ -0,0 +1,32 @@
+package oidc
+
+import "net/http"
+
+// Client drives the Authorization Code + PKCE flow against a discovered
+// Keycloak realm.
+type Client struct {
+	Discovery    *Discovery
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+
+	// CookieSecret signs the short-lived login-session cookie used to carry
+	// state/nonce/PKCE verifier between /auth/login and /auth/callback.
+	CookieSecret []byte
+
+	HTTPClient *http.Client
+}
+
+// NewClient builds a Client from an already-fetched Discovery document, so
+// callers that also need the discovery result elsewhere (e.g. to populate
+// middleware's endpoint variables) only fetch it once.
+func NewClient(discovery *Discovery, clientID, clientSecret, redirectURL string, cookieSecret []byte) *Client {
+	return &Client{
+		Discovery:    discovery,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		CookieSecret: cookieSecret,
+		HTTPClient:   &http.Client{},
+	}
+}
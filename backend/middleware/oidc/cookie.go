@@ -0,0 +1,90 @@
+package oidc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loginSession is the state carried between /auth/login and /auth/callback
+// in a signed, short-lived cookie.
+type loginSession struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+const (
+	loginCookieName   = "oidc_login"
+	loginCookieMaxAge = 5 * 60 // seconds
+)
+
+// setLoginCookie signs and stores sess in a short-lived cookie.
+func (c *Client) setLoginCookie(ctx *gin.Context, sess loginSession) error {
+	payload, err := json.Marshal(sess)
+	if err != nil {
+		return fmt.Errorf("failed to encode login session: %w", err)
+	}
+	ctx.SetCookie(loginCookieName, signAndEncode(payload, c.CookieSecret), loginCookieMaxAge, "/", "", false, true)
+	return nil
+}
+
+// readLoginCookie verifies and decodes the login-session cookie set by
+// setLoginCookie.
+func (c *Client) readLoginCookie(ctx *gin.Context) (loginSession, error) {
+	var sess loginSession
+
+	raw, err := ctx.Cookie(loginCookieName)
+	if err != nil {
+		return sess, fmt.Errorf("missing or expired login session cookie: %w", err)
+	}
+
+	payload, err := decodeAndVerify(raw, c.CookieSecret)
+	if err != nil {
+		return sess, err
+	}
+
+	if err := json.Unmarshal(payload, &sess); err != nil {
+		return sess, fmt.Errorf("failed to decode login session: %w", err)
+	}
+
+	return sess, nil
+}
+
+func (c *Client) clearLoginCookie(ctx *gin.Context) {
+	ctx.SetCookie(loginCookieName, "", -1, "/", "", false, true)
+}
+
+// signAndEncode HMAC-signs payload and returns a single base64 string
+// carrying both the signature and the payload.
+func signAndEncode(payload, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	combined := append(mac.Sum(nil), payload...)
+	return base64.RawURLEncoding.EncodeToString(combined)
+}
+
+// decodeAndVerify reverses signAndEncode, rejecting a payload whose
+// signature doesn't match.
+func decodeAndVerify(value string, secret []byte) ([]byte, error) {
+	combined, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cookie: %w", err)
+	}
+	if len(combined) < sha256.Size {
+		return nil, fmt.Errorf("cookie is too short to contain a signature")
+	}
+
+	sig, payload := combined[:sha256.Size], combined[sha256.Size:]
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, fmt.Errorf("cookie signature mismatch")
+	}
+
+	return payload, nil
+}
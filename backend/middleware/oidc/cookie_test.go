@@ -0,0 +1,56 @@
+package oidc
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestSignAndEncodeRoundTrips(t *testing.T) {
+	secret := []byte("test-secret")
+	payload := []byte(`{"state":"abc"}`)
+
+	encoded := signAndEncode(payload, secret)
+
+	got, err := decodeAndVerify(encoded, secret)
+	if err != nil {
+		t.Fatalf("decodeAndVerify() error = %v", err)
+	}
+	if string(got) != string(payload) {
+		t.Errorf("decodeAndVerify() = %q, want %q", got, payload)
+	}
+}
+
+func TestDecodeAndVerifyRejectsTamperedPayload(t *testing.T) {
+	secret := []byte("test-secret")
+	encoded := signAndEncode([]byte(`{"state":"abc"}`), secret)
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("failed to decode test fixture: %v", err)
+	}
+	raw[len(raw)-1] ^= 0xFF // flip a bit in the payload, leaving the signature alone
+	tampered := base64.RawURLEncoding.EncodeToString(raw)
+
+	if _, err := decodeAndVerify(tampered, secret); err == nil {
+		t.Error("decodeAndVerify() accepted a tampered payload")
+	}
+}
+
+func TestDecodeAndVerifyRejectsWrongSecret(t *testing.T) {
+	encoded := signAndEncode([]byte(`{"state":"abc"}`), []byte("secret-a"))
+
+	if _, err := decodeAndVerify(encoded, []byte("secret-b")); err == nil {
+		t.Error("decodeAndVerify() accepted a cookie signed with a different secret")
+	}
+}
+
+func TestDecodeAndVerifyRejectsGarbage(t *testing.T) {
+	if _, err := decodeAndVerify("not-valid-base64!!", []byte("secret")); err == nil {
+		t.Error("decodeAndVerify() accepted invalid base64")
+	}
+
+	tooShort := base64.RawURLEncoding.EncodeToString([]byte("short"))
+	if _, err := decodeAndVerify(tooShort, []byte("secret")); err == nil {
+		t.Error("decodeAndVerify() accepted a payload shorter than a signature")
+	}
+}
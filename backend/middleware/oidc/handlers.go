@@ -0,0 +1,144 @@
+package oidc
+
+import (
+	"net/http"
+	"net/url"
+
+	"keycloak-basic-backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionCookieName is the cookie the callback handler issues once login
+// succeeds. It holds the raw access token; extractBearerToken
+// (middleware/helper.go) reads it back when there's no Authorization
+// header, so browser callers never have to hand-craft a bearer token.
+const sessionCookieName = middleware.SessionCookieName
+
+// LoginHandler generates state, a nonce, and a PKCE code_verifier/
+// code_challenge, stores them in a signed cookie, and redirects to
+// Keycloak's authorization endpoint.
+func (c *Client) LoginHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		state, err := generateRandomString(16)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start login", "detail": err.Error()})
+			return
+		}
+		nonce, err := generateRandomString(16)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start login", "detail": err.Error()})
+			return
+		}
+		verifier, challenge, err := newPKCEVerifier()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start login", "detail": err.Error()})
+			return
+		}
+
+		if err := c.setLoginCookie(ctx, loginSession{State: state, Nonce: nonce, Verifier: verifier}); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start login", "detail": err.Error()})
+			return
+		}
+
+		authURL, err := url.Parse(c.Discovery.AuthorizationEndpoint)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "invalid authorization endpoint", "detail": err.Error()})
+			return
+		}
+
+		q := authURL.Query()
+		q.Set("response_type", "code")
+		q.Set("client_id", c.ClientID)
+		q.Set("redirect_uri", c.RedirectURL)
+		q.Set("scope", "openid profile email")
+		q.Set("state", state)
+		q.Set("nonce", nonce)
+		q.Set("code_challenge", challenge)
+		q.Set("code_challenge_method", "S256")
+		authURL.RawQuery = q.Encode()
+
+		ctx.Redirect(http.StatusFound, authURL.String())
+	}
+}
+
+// CallbackHandler validates state, exchanges the authorization code for a
+// token set using the stored PKCE verifier, verifies the id_token's
+// signature and nonce, and issues a session cookie.
+func (c *Client) CallbackHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		sess, err := c.readLoginCookie(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid or expired login session", "detail": err.Error()})
+			return
+		}
+		c.clearLoginCookie(ctx)
+
+		if errParam := ctx.Query("error"); errParam != "" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization failed", "detail": ctx.Query("error_description")})
+			return
+		}
+
+		if ctx.Query("state") != sess.State {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "state mismatch"})
+			return
+		}
+
+		code := ctx.Query("code")
+		if code == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing authorization code"})
+			return
+		}
+
+		tokenResp, err := c.exchangeCode(code, sess.Verifier)
+		if err != nil {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to exchange authorization code", "detail": err.Error()})
+			return
+		}
+
+		claims, err := c.verifyIDToken(tokenResp.IDToken, sess.Nonce)
+		if err != nil {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to verify id_token", "detail": err.Error()})
+			return
+		}
+
+		ctx.SetCookie(sessionCookieName, tokenResp.AccessToken, int(tokenResp.ExpiresIn), "/", "", false, true)
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"message": "login successful",
+			"user": gin.H{
+				"sub":   claims.Subject,
+				"email": claims.Email,
+			},
+		})
+	}
+}
+
+// LogoutHandler clears the session cookie and redirects to Keycloak's
+// end-session endpoint to terminate the SSO session too.
+func (c *Client) LogoutHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		idTokenHint := ctx.Query("id_token_hint")
+		ctx.SetCookie(sessionCookieName, "", -1, "/", "", false, true)
+
+		if c.Discovery.EndSessionEndpoint == "" {
+			ctx.JSON(http.StatusOK, gin.H{"message": "logged out"})
+			return
+		}
+
+		endSessionURL, err := url.Parse(c.Discovery.EndSessionEndpoint)
+		if err != nil {
+			ctx.JSON(http.StatusOK, gin.H{"message": "logged out"})
+			return
+		}
+
+		q := endSessionURL.Query()
+		if idTokenHint != "" {
+			q.Set("id_token_hint", idTokenHint)
+		}
+		q.Set("post_logout_redirect_uri", c.RedirectURL)
+		endSessionURL.RawQuery = q.Encode()
+
+		ctx.Redirect(http.StatusFound, endSessionURL.String())
+	}
+}
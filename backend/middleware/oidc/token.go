@@ -0,0 +1,119 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"keycloak-basic-backend/middleware"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// tokenExchangeResponse is Keycloak's response to the authorization_code
+// grant.
+type tokenExchangeResponse struct {
+	AccessToken  string `json:"access_token"`
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	TokenType    string `json:"token_type"`
+}
+
+// exchangeCode swaps an authorization code (plus its PKCE verifier) for a
+// token set at the provider's token endpoint.
+func (c *Client) exchangeCode(code, verifier string) (*tokenExchangeResponse, error) {
+	data := url.Values{}
+	data.Set("grant_type", "authorization_code")
+	data.Set("client_id", c.ClientID)
+	data.Set("client_secret", c.ClientSecret)
+	data.Set("code", code)
+	data.Set("redirect_uri", c.RedirectURL)
+	data.Set("code_verifier", verifier)
+
+	req, err := http.NewRequest("POST", c.Discovery.TokenEndpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token exchange request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach token endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token exchange failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp tokenExchangeResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+
+	return &tokenResp, nil
+}
+
+// verifyIDToken validates idToken's signature against the shared JWKS cache
+// and checks that its nonce matches the one generated at /auth/login.
+func (c *Client) verifyIDToken(idToken, expectedNonce string) (*middleware.CustomClaims, error) {
+	manager := middleware.GlobalJWKSManager()
+	if manager == nil {
+		return nil, fmt.Errorf("JWKS manager not initialized")
+	}
+
+	token, err := jwt.ParseWithClaims(idToken, &middleware.CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, fmt.Errorf("kid header not found in id_token")
+		}
+
+		key, ok := manager.Key(kid)
+		if !ok {
+			return nil, fmt.Errorf("public key not found for kid: %s", kid)
+		}
+
+		return key, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse id_token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*middleware.CustomClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid id_token claims")
+	}
+
+	if claims.Issuer != c.Discovery.Issuer {
+		return nil, fmt.Errorf("invalid issuer: expected %s, got %s", c.Discovery.Issuer, claims.Issuer)
+	}
+
+	validAudience := false
+	for _, aud := range claims.Audience {
+		if aud == c.ClientID {
+			validAudience = true
+			break
+		}
+	}
+	if !validAudience {
+		return nil, fmt.Errorf("invalid audience: id_token is not intended for client %s", c.ClientID)
+	}
+
+	if claims.Nonce != expectedNonce {
+		return nil, fmt.Errorf("nonce mismatch")
+	}
+
+	return claims, nil
+}
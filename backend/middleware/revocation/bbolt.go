@@ -0,0 +1,116 @@
+package revocation
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	jtiBucket     = []byte("revoked_jtis")
+	subjectBucket = []byte("revoked_subjects")
+)
+
+// BoltStore persists revocations in a bbolt database file, so the revocation
+// list survives process restarts.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a bbolt database at path.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bbolt database: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(jtiBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(subjectBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create revocation buckets: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Close releases the underlying database file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *BoltStore) Revoke(jti string, exp time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jtiBucket).Put([]byte(jti), encodeExpiry(exp))
+	})
+}
+
+func (s *BoltStore) IsRevoked(jti string) (bool, error) {
+	return isRevoked(s.db, jtiBucket, jti)
+}
+
+func (s *BoltStore) RevokeSubject(sub string, until time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(subjectBucket).Put([]byte(sub), encodeExpiry(until))
+	})
+}
+
+func (s *BoltStore) IsSubjectRevoked(sub string) (bool, error) {
+	return isRevoked(s.db, subjectBucket, sub)
+}
+
+func isRevoked(db *bolt.DB, bucket []byte, key string) (bool, error) {
+	var revoked bool
+	err := db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucket).Get([]byte(key))
+		if v == nil {
+			return nil
+		}
+		revoked = time.Now().Before(decodeExpiry(v))
+		return nil
+	})
+	return revoked, err
+}
+
+func (s *BoltStore) GC(ctx context.Context) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		now := time.Now()
+		for _, bucket := range [][]byte{jtiBucket, subjectBucket} {
+			b := tx.Bucket(bucket)
+			var expiredKeys [][]byte
+			err := b.ForEach(func(k, v []byte) error {
+				if now.After(decodeExpiry(v)) {
+					expiredKeys = append(expiredKeys, append([]byte(nil), k...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range expiredKeys {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func encodeExpiry(t time.Time) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(t.Unix()))
+	return buf
+}
+
+func decodeExpiry(b []byte) time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint64(b)), 0)
+}
@@ -0,0 +1,49 @@
+package revocation
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBoltStoreGCPrunesExpiredEntries(t *testing.T) {
+	s, err := NewBoltStore(filepath.Join(t.TempDir(), "revocation.db"))
+	if err != nil {
+		t.Fatalf("NewBoltStore() error = %v", err)
+	}
+	defer s.Close()
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	if err := s.Revoke("expired-jti", past); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if err := s.Revoke("live-jti", future); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if err := s.RevokeSubject("expired-sub", past); err != nil {
+		t.Fatalf("RevokeSubject() error = %v", err)
+	}
+	if err := s.RevokeSubject("live-sub", future); err != nil {
+		t.Fatalf("RevokeSubject() error = %v", err)
+	}
+
+	if err := s.GC(context.Background()); err != nil {
+		t.Fatalf("GC() error = %v", err)
+	}
+
+	if revoked, _ := isRevoked(s.db, jtiBucket, "expired-jti"); revoked {
+		t.Error("expired jti survived GC")
+	}
+	if revoked, _ := isRevoked(s.db, jtiBucket, "live-jti"); !revoked {
+		t.Error("live jti was pruned by GC")
+	}
+	if revoked, _ := isRevoked(s.db, subjectBucket, "expired-sub"); revoked {
+		t.Error("expired subject survived GC")
+	}
+	if revoked, _ := isRevoked(s.db, subjectBucket, "live-sub"); !revoked {
+		t.Error("live subject was pruned by GC")
+	}
+}
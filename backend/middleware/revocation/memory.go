@@ -0,0 +1,69 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory Store, suitable for a single-instance demo or
+// for tests. Revocations do not survive a restart.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	jtis     map[string]time.Time
+	subjects map[string]time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jtis:     make(map[string]time.Time),
+		subjects: make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryStore) Revoke(jti string, exp time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jtis[jti] = exp
+	return nil
+}
+
+func (s *MemoryStore) IsRevoked(jti string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	exp, ok := s.jtis[jti]
+	return ok && time.Now().Before(exp), nil
+}
+
+func (s *MemoryStore) RevokeSubject(sub string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subjects[sub] = until
+	return nil
+}
+
+func (s *MemoryStore) IsSubjectRevoked(sub string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	until, ok := s.subjects[sub]
+	return ok && time.Now().Before(until), nil
+}
+
+func (s *MemoryStore) GC(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for jti, exp := range s.jtis {
+		if now.After(exp) {
+			delete(s.jtis, jti)
+		}
+	}
+	for sub, until := range s.subjects {
+		if now.After(until) {
+			delete(s.subjects, sub)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package revocation
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreGCPrunesExpiredEntries(t *testing.T) {
+	s := NewMemoryStore()
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	if err := s.Revoke("expired-jti", past); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if err := s.Revoke("live-jti", future); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if err := s.RevokeSubject("expired-sub", past); err != nil {
+		t.Fatalf("RevokeSubject() error = %v", err)
+	}
+	if err := s.RevokeSubject("live-sub", future); err != nil {
+		t.Fatalf("RevokeSubject() error = %v", err)
+	}
+
+	if err := s.GC(context.Background()); err != nil {
+		t.Fatalf("GC() error = %v", err)
+	}
+
+	if _, ok := s.jtis["expired-jti"]; ok {
+		t.Error("expired jti survived GC")
+	}
+	if _, ok := s.jtis["live-jti"]; !ok {
+		t.Error("live jti was pruned by GC")
+	}
+	if _, ok := s.subjects["expired-sub"]; ok {
+		t.Error("expired subject survived GC")
+	}
+	if _, ok := s.subjects["live-sub"]; !ok {
+		t.Error("live subject was pruned by GC")
+	}
+}
+
+func TestMemoryStoreIsRevoked(t *testing.T) {
+	s := NewMemoryStore()
+	if revoked, err := s.IsRevoked("unknown"); err != nil || revoked {
+		t.Errorf("IsRevoked(unknown) = %v, %v; want false, nil", revoked, err)
+	}
+
+	if err := s.Revoke("jti", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if revoked, err := s.IsRevoked("jti"); err != nil || revoked {
+		t.Errorf("IsRevoked(jti) after expiry = %v, %v; want false, nil", revoked, err)
+	}
+
+	if err := s.Revoke("jti2", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if revoked, err := s.IsRevoked("jti2"); err != nil || !revoked {
+		t.Errorf("IsRevoked(jti2) = %v, %v; want true, nil", revoked, err)
+	}
+}
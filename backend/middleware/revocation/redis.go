@@ -0,0 +1,67 @@
+package revocation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore stores revocations as keys with a TTL matching the token's (or
+// subject revocation's) remaining lifetime, so Redis prunes expired entries
+// on its own; GC is a no-op.
+type RedisStore struct {
+	client        *redis.Client
+	jtiPrefix     string
+	subjectPrefix string
+}
+
+// NewRedisStore wraps an existing *redis.Client for use as a revocation Store.
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{
+		client:        client,
+		jtiPrefix:     "revoked:jti:",
+		subjectPrefix: "revoked:sub:",
+	}
+}
+
+func (s *RedisStore) Revoke(jti string, exp time.Time) error {
+	return s.setWithTTL(s.jtiPrefix+jti, exp)
+}
+
+func (s *RedisStore) IsRevoked(jti string) (bool, error) {
+	return s.exists(s.jtiPrefix + jti)
+}
+
+func (s *RedisStore) RevokeSubject(sub string, until time.Time) error {
+	return s.setWithTTL(s.subjectPrefix+sub, until)
+}
+
+func (s *RedisStore) IsSubjectRevoked(sub string) (bool, error) {
+	return s.exists(s.subjectPrefix + sub)
+}
+
+// GC is a no-op: Redis expires keys via TTL on its own.
+func (s *RedisStore) GC(ctx context.Context) error {
+	return nil
+}
+
+func (s *RedisStore) setWithTTL(key string, expiry time.Time) error {
+	ttl := time.Until(expiry)
+	if ttl <= 0 {
+		return nil
+	}
+	if err := s.client.Set(context.Background(), key, "1", ttl).Err(); err != nil {
+		return fmt.Errorf("failed to record revocation: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) exists(key string) (bool, error) {
+	n, err := s.client.Exists(context.Background(), key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check revocation status: %w", err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,33 @@
+// Package revocation gives the offline JWT verification path revocation
+// semantics it otherwise lacks, without paying the round-trip cost of
+// introspection on every request. Callers record a token's jti (or a user's
+// sub, to cover "all sessions") in a Store, and JWTAuthMiddleware rejects
+// tokens found there.
+package revocation
+
+import (
+	"context"
+	"time"
+)
+
+// Store is a pluggable backend for revoked-token bookkeeping.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Revoke records jti as revoked until exp, after which GC may prune it.
+	Revoke(jti string, exp time.Time) error
+
+	// IsRevoked reports whether jti has been revoked and not yet pruned.
+	IsRevoked(jti string) (bool, error)
+
+	// RevokeSubject records every token belonging to sub as revoked until
+	// until, covering the "revoke all sessions for this user" case.
+	RevokeSubject(sub string, until time.Time) error
+
+	// IsSubjectRevoked reports whether sub currently has an active
+	// subject-wide revocation.
+	IsSubjectRevoked(sub string) (bool, error)
+
+	// GC removes entries whose expiry has passed, so the store doesn't grow
+	// unbounded. Callers are expected to invoke this periodically.
+	GC(ctx context.Context) error
+}
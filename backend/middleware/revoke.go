@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"keycloak-basic-backend/middleware/revocation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ===========================
+// Revocation
+// ===========================
+//
+// revocationStore, if set, gives the offline JWT path revocation awareness:
+// JWTAuthMiddleware rejects any token whose jti (or whose subject) is found
+// there. It's nil by default so existing deployments that don't configure a
+// store see no behavior change.
+
+var revocationStore revocation.Store
+
+// SetRevocationStore configures the backend JWTAuthMiddleware and
+// RevokeTokenHandler use to track revoked tokens. Pass nil to disable
+// revocation checks.
+func SetRevocationStore(store revocation.Store) {
+	revocationStore = store
+}
+
+// RevokeTokenHandler revokes the current request's token (by jti) and,
+// optionally, every token belonging to the same subject. It must run after
+// JWTAuthMiddleware, which populates the "jti", "exp", and "user_id" context
+// values this handler relies on.
+func RevokeTokenHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if revocationStore == nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Revocation store is not configured",
+			})
+			return
+		}
+
+		var req struct {
+			AllSessions bool `json:"all_sessions"`
+		}
+		// Body is optional; default to revoking just the current token.
+		_ = c.ShouldBindJSON(&req)
+
+		exp, _ := c.Get("exp")
+		expTime, ok := exp.(time.Time)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Missing token expiry in context",
+			})
+			return
+		}
+
+		jti, _ := c.Get("jti")
+		jtiStr, hasJTI := jti.(string)
+		hasJTI = hasJTI && jtiStr != ""
+		if hasJTI {
+			if err := revocationStore.Revoke(jtiStr, expTime); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":  "Failed to revoke token",
+					"detail": err.Error(),
+				})
+				return
+			}
+		} else if !req.AllSessions {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": "Token has no jti to revoke; pass all_sessions to revoke by subject instead",
+			})
+			return
+		}
+
+		if req.AllSessions {
+			userID, _ := c.Get("user_id")
+			if sub, ok := userID.(string); ok && sub != "" {
+				if err := revocationStore.RevokeSubject(sub, expTime); err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{
+						"error":  "Failed to revoke sessions",
+						"detail": err.Error(),
+					})
+					return
+				}
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "Token revoked",
+			"all_sessions": req.AllSessions,
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// ===========================
+// Introspection/Revocation Cache
+// ===========================
+//
+// A small in-memory cache of recent introspection results, keyed by a hash of
+// the token itself. TokenIntrospectionMiddleware populates it on every
+// successful call to Keycloak; JWTAuthMiddleware can then consult it to pick
+// up revocations without paying the introspection round-trip on every
+// request, combining the JWT path's speed with some of the introspection
+// path's revocation awareness.
+
+// introspectionCacheTTL bounds how long a cached introspection result is
+// trusted before JWTAuthMiddleware falls back to trusting the signature alone.
+const introspectionCacheTTL = 30 * time.Second
+
+// cachedIntrospection is a cached {active, exp} result for a single token.
+type cachedIntrospection struct {
+	active   bool
+	exp      time.Time
+	cachedAt time.Time
+}
+
+// introspectionCache is a mutex-guarded map of token hash to its last known
+// introspection result.
+type introspectionCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedIntrospection
+}
+
+func newIntrospectionCache() *introspectionCache {
+	return &introspectionCache{
+		entries: make(map[string]cachedIntrospection),
+	}
+}
+
+// get returns the cached result for tokenHash, if any and not yet stale.
+func (c *introspectionCache) get(tokenHash string) (cachedIntrospection, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[tokenHash]
+	if !ok {
+		return cachedIntrospection{}, false
+	}
+	if time.Since(entry.cachedAt) > introspectionCacheTTL {
+		delete(c.entries, tokenHash)
+		return cachedIntrospection{}, false
+	}
+	return entry, true
+}
+
+// set records the introspection result for tokenHash.
+func (c *introspectionCache) set(tokenHash string, active bool, exp time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[tokenHash] = cachedIntrospection{
+		active:   active,
+		exp:      exp,
+		cachedAt: time.Now(),
+	}
+}
+
+// globalIntrospectionCache is the shared cache consulted/populated by the JWT
+// and introspection middlewares.
+var globalIntrospectionCache = newIntrospectionCache()
+
+// hashToken returns a hex-encoded SHA-256 digest of token, so the cache never
+// stores raw bearer tokens.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,51 @@
+package tokenclient
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serviceHTTPClientKey is the gin context key under which WithServiceToken
+// stores the authenticated *http.Client.
+const serviceHTTPClientKey = "service_http_client"
+
+// WithServiceToken returns a gin.HandlerFunc that attaches an *http.Client to
+// the request context whose outbound requests are automatically stamped with
+// a bearer token from src. Handlers that need to call another service on
+// Keycloak's behalf can fetch it with ServiceHTTPClient(c).
+func WithServiceToken(src *TokenSource) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(serviceHTTPClientKey, &http.Client{
+			Transport: &bearerRoundTripper{src: src, base: http.DefaultTransport},
+		})
+		c.Next()
+	}
+}
+
+// ServiceHTTPClient returns the *http.Client stashed by WithServiceToken, or
+// nil if that middleware wasn't used on this route.
+func ServiceHTTPClient(c *gin.Context) *http.Client {
+	client, _ := c.Get(serviceHTTPClientKey)
+	httpClient, _ := client.(*http.Client)
+	return httpClient
+}
+
+// bearerRoundTripper injects a fresh bearer token from src into every
+// outbound request before delegating to base.
+type bearerRoundTripper struct {
+	src  *TokenSource
+	base http.RoundTripper
+}
+
+func (t *bearerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.src.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain service token: %w", err)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
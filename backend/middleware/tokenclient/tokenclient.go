@@ -0,0 +1,117 @@
+// Package tokenclient obtains and refreshes access tokens against Keycloak
+// for outbound calls, complementing the inbound token verification in
+// package middleware. It covers the password and client-credentials grants,
+// refresh-token rotation, and a TokenSource helper that keeps a token fresh
+// across repeated use.
+package tokenclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"keycloak-basic-backend/middleware"
+)
+
+// ===========================
+// Data Structures
+// ===========================
+
+// TokenResponse mirrors Keycloak's OAuth2 token endpoint response.
+type TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"`
+}
+
+// grantError represents Keycloak's OAuth2 error response, e.g.
+// {"error": "invalid_grant", "error_description": "..."}
+type grantError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// ===========================
+// Grants
+// ===========================
+
+// PasswordGrant exchanges a username/password for a token pair using the
+// OAuth2 Resource Owner Password Credentials grant.
+func PasswordGrant(username, password string) (*TokenResponse, error) {
+	data := url.Values{}
+	data.Set("grant_type", "password")
+	data.Set("client_id", middleware.ClientID())
+	data.Set("client_secret", middleware.ClientSecret())
+	data.Set("username", username)
+	data.Set("password", password)
+
+	return requestToken(data)
+}
+
+// ClientCredentialsGrant obtains a service (client-only) access token using
+// the OAuth2 Client Credentials grant. Useful for server-to-server calls that
+// aren't made on behalf of a specific user.
+func ClientCredentialsGrant() (*TokenResponse, error) {
+	data := url.Values{}
+	data.Set("grant_type", "client_credentials")
+	data.Set("client_id", middleware.ClientID())
+	data.Set("client_secret", middleware.ClientSecret())
+
+	return requestToken(data)
+}
+
+// RefreshToken exchanges a refresh token for a new token pair. Keycloak
+// returns an "invalid_grant" error if the refresh token has expired or been
+// revoked, in which case the caller should fall back to a fresh grant.
+func RefreshToken(refreshToken string) (*TokenResponse, error) {
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("client_id", middleware.ClientID())
+	data.Set("client_secret", middleware.ClientSecret())
+	data.Set("refresh_token", refreshToken)
+
+	return requestToken(data)
+}
+
+// requestToken POSTs a grant request to Keycloak's token endpoint and parses
+// the response, surfacing Keycloak's error/error_description on failure.
+func requestToken(data url.Values) (*TokenResponse, error) {
+	req, err := http.NewRequest("POST", middleware.TokenURL(), strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach token endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var grantErr grantError
+		if jsonErr := json.Unmarshal(body, &grantErr); jsonErr == nil && grantErr.Error != "" {
+			return nil, fmt.Errorf("token request failed: %s: %s", grantErr.Error, grantErr.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+
+	return &tokenResp, nil
+}
@@ -0,0 +1,80 @@
+package tokenclient
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// refreshSkew is how far ahead of expiry TokenSource refreshes, so a caller
+// never hands out a token that's about to expire mid-request.
+const refreshSkew = 30 * time.Second
+
+// TokenSource caches a token obtained from a grant and transparently
+// refreshes it once it's within refreshSkew of expiring, guarding concurrent
+// access with a mutex so only one refresh happens at a time.
+type TokenSource struct {
+	mu sync.Mutex
+
+	current    *TokenResponse
+	obtainedAt time.Time
+
+	initialGrant func() (*TokenResponse, error)
+	refreshFn    func(refreshToken string) (*TokenResponse, error)
+}
+
+// NewTokenSource builds a TokenSource from an initial grant function, e.g.
+// tokenclient.ClientCredentialsGrant, or a closure binding PasswordGrant to a
+// fixed set of credentials.
+func NewTokenSource(initialGrant func() (*TokenResponse, error)) *TokenSource {
+	return &TokenSource{
+		initialGrant: initialGrant,
+		refreshFn:    RefreshToken,
+	}
+}
+
+// Token returns a currently-valid access token, obtaining or refreshing it as
+// needed.
+func (s *TokenSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current == nil {
+		return s.grant()
+	}
+
+	expiresAt := s.obtainedAt.Add(time.Duration(s.current.ExpiresIn) * time.Second)
+	if time.Until(expiresAt) > refreshSkew {
+		return s.current.AccessToken, nil
+	}
+
+	if s.current.RefreshToken == "" {
+		return s.grant()
+	}
+
+	tok, err := s.refreshFn(s.current.RefreshToken)
+	if err != nil {
+		// Keycloak returns invalid_grant when the refresh token itself has
+		// expired or been revoked; fall back to a fresh grant in that case
+		// rather than forcing the caller to handle re-auth.
+		if strings.Contains(err.Error(), "invalid_grant") {
+			return s.grant()
+		}
+		return "", err
+	}
+
+	s.current = tok
+	s.obtainedAt = time.Now()
+	return s.current.AccessToken, nil
+}
+
+// grant runs the initial grant and stores the result. Callers must hold s.mu.
+func (s *TokenSource) grant() (string, error) {
+	tok, err := s.initialGrant()
+	if err != nil {
+		return "", err
+	}
+	s.current = tok
+	s.obtainedAt = time.Now()
+	return s.current.AccessToken, nil
+}
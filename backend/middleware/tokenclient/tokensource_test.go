@@ -0,0 +1,113 @@
+package tokenclient
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenSourceObtainsInitialGrant(t *testing.T) {
+	calls := 0
+	s := NewTokenSource(func() (*TokenResponse, error) {
+		calls++
+		return &TokenResponse{AccessToken: "first", ExpiresIn: 300}, nil
+	})
+
+	tok, err := s.Token()
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if tok != "first" {
+		t.Errorf("Token() = %q, want %q", tok, "first")
+	}
+	if calls != 1 {
+		t.Errorf("initialGrant called %d times, want 1", calls)
+	}
+
+	// A second call within refreshSkew of expiry should reuse the cached
+	// token rather than calling the grant func again.
+	if _, err := s.Token(); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("initialGrant called %d times on cache hit, want 1", calls)
+	}
+}
+
+func TestTokenSourceRefreshesNearExpiry(t *testing.T) {
+	s := NewTokenSource(func() (*TokenResponse, error) {
+		return &TokenResponse{AccessToken: "stale", RefreshToken: "rt", ExpiresIn: 300}, nil
+	})
+	if _, err := s.Token(); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	// Force the cached token to look like it's about to expire.
+	s.obtainedAt = time.Now().Add(-280 * time.Second)
+
+	refreshed := false
+	s.refreshFn = func(refreshToken string) (*TokenResponse, error) {
+		refreshed = true
+		if refreshToken != "rt" {
+			t.Errorf("refreshFn called with %q, want %q", refreshToken, "rt")
+		}
+		return &TokenResponse{AccessToken: "fresh", RefreshToken: "rt2", ExpiresIn: 300}, nil
+	}
+
+	tok, err := s.Token()
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if !refreshed {
+		t.Fatal("refreshFn was not called")
+	}
+	if tok != "fresh" {
+		t.Errorf("Token() = %q, want %q", tok, "fresh")
+	}
+}
+
+func TestTokenSourceFallsBackToGrantOnInvalidGrant(t *testing.T) {
+	grants := 0
+	s := NewTokenSource(func() (*TokenResponse, error) {
+		grants++
+		return &TokenResponse{AccessToken: "regranted", ExpiresIn: 300}, nil
+	})
+	if _, err := s.Token(); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	s.current.RefreshToken = "rt"
+	s.obtainedAt = time.Now().Add(-280 * time.Second)
+
+	s.refreshFn = func(string) (*TokenResponse, error) {
+		return nil, errors.New("token request failed: invalid_grant: Refresh token expired")
+	}
+
+	tok, err := s.Token()
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if tok != "regranted" {
+		t.Errorf("Token() = %q, want %q", tok, "regranted")
+	}
+	if grants != 2 {
+		t.Errorf("initialGrant called %d times, want 2 (initial + invalid_grant fallback)", grants)
+	}
+}
+
+func TestTokenSourcePropagatesOtherRefreshErrors(t *testing.T) {
+	s := NewTokenSource(func() (*TokenResponse, error) {
+		return &TokenResponse{AccessToken: "first", RefreshToken: "rt", ExpiresIn: 300}, nil
+	})
+	if _, err := s.Token(); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	s.obtainedAt = time.Now().Add(-280 * time.Second)
+
+	wantErr := errors.New("network unreachable")
+	s.refreshFn = func(string) (*TokenResponse, error) {
+		return nil, wantErr
+	}
+
+	if _, err := s.Token(); !errors.Is(err, wantErr) {
+		t.Errorf("Token() error = %v, want %v", err, wantErr)
+	}
+}
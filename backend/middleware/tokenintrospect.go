@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,6 +28,7 @@ type IntrospectionResponse struct {
 	TokenType string `json:"token_type"` // Token type (Bearer)
 	Sub       string `json:"sub"`        // Subject (user ID)
 	Email     string `json:"email"`      // User email
+	Scope     string `json:"scope"`      // Space-separated granted scopes
 }
 
 // TokenIntrospectionMiddleware validates access tokens using Keycloak's introspection endpoint
@@ -55,6 +57,10 @@ func TokenIntrospectionMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Cache the result so the JWT path can pick up this revocation
+		// without a round-trip to Keycloak on every request.
+		globalIntrospectionCache.set(hashToken(token), introspectResp.Active, time.Unix(introspectResp.Exp, 0))
+
 		// Step 4: Check if token is active
 		if !introspectResp.Active {
 			c.JSON(http.StatusUnauthorized, gin.H{
@@ -68,6 +74,7 @@ func TokenIntrospectionMiddleware() gin.HandlerFunc {
 		// Store user info in context for use in handlers
 		c.Set("user_id", introspectResp.Sub)
 		c.Set("email", introspectResp.Email)
+		c.Set("scopes", strings.Fields(introspectResp.Scope))
 
 		// Token is valid and active, continue to the next handler
 		c.Next()